@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mohitsharma-in/k-org/internal/bom"
+)
+
+// bomOptions configures the bom subcommand. It's kept separate from
+// Options since it scans a module's dependencies rather than a source tree.
+type bomOptions struct {
+	path      string
+	threshold float64
+	format    string
+}
+
+var bomOpts = &bomOptions{}
+
+func addBOMCommand(rootCmd *cobra.Command) {
+	bomCmd := &cobra.Command{
+		Use:   "bom",
+		Short: "Generate a bill-of-materials of third-party licenses",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return bomOpts.Run()
+		},
+	}
+	bomCmd.Flags().StringVar(&bomOpts.path, "path", ".", "root of the Go module to scan; its vendor/ directory is used when present")
+	bomCmd.Flags().Float64Var(&bomOpts.threshold, "threshold", 0.9, "minimum confidence to accept a license match; below this the entry is flagged UNKNOWN")
+	bomCmd.Flags().StringVar(&bomOpts.format, "format", "json", "output format: json, markdown")
+	rootCmd.AddCommand(bomCmd)
+}
+
+func (o *bomOptions) Run() error {
+	modules, err := bom.Dependencies(o.path)
+	if err != nil {
+		return err
+	}
+
+	deps := make([]bom.Dependency, 0, len(modules))
+	flagged := 0
+	for _, m := range modules {
+		text, err := bom.FindLicenseFile(m.Dir)
+		if err != nil {
+			return err
+		}
+		licenseType, confidence := bom.Unknown, 0.0
+		if text != "" {
+			licenseType, confidence = bom.Classify(text, o.threshold)
+		}
+		if licenseType == bom.Unknown {
+			flagged++
+		}
+		deps = append(deps, bom.Dependency{
+			Project:     m.Path,
+			Version:     m.Version,
+			LicenseType: licenseType,
+			Confidence:  confidence,
+			LicenseText: text,
+		})
+	}
+
+	switch o.format {
+	case "markdown":
+		printBOMMarkdown(deps)
+	case "json":
+		if err := json.NewEncoder(os.Stdout).Encode(deps); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown --format %q, must be one of json, markdown", o.format)
+	}
+
+	if flagged > 0 {
+		fmt.Fprintf(os.Stderr, "%d dependencies have a missing, unrecognized or multi-license file and need manual review\n", flagged)
+	}
+	return nil
+}
+
+func printBOMMarkdown(deps []bom.Dependency) {
+	fmt.Println("| Project | Version | License | Confidence |")
+	fmt.Println("|---|---|---|---|")
+	for _, d := range deps {
+		licenseType := string(d.LicenseType)
+		if d.LicenseType == bom.Unknown {
+			licenseType = "UNKNOWN (needs review)"
+		}
+		fmt.Printf("| %s | %s | %s | %.2f |\n", d.Project, d.Version, licenseType, d.Confidence)
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Check scans opts.path and reports every finding in opts.format, and exits
+// with status 1 if any file fails, so it can be wired up as a pre-commit hook
+// or CI gate. It prints no report when every file passes, but the progress
+// lines Scan prints for skipped files (excluded, generated or already
+// licensed) still go to stdout, ahead of the report in every format.
+func (opts *Options) Check() error {
+	findings, err := opts.Scan(false)
+	if err != nil {
+		return err
+	}
+
+	switch opts.format {
+	case "json":
+		if len(findings) > 0 {
+			if err := json.NewEncoder(os.Stdout).Encode(findings); err != nil {
+				return err
+			}
+		}
+	case "github":
+		for _, f := range findings {
+			fmt.Printf("::error file=%s::%s\n", f.Path, f.Reason)
+		}
+	case "text":
+		for _, f := range findings {
+			fmt.Printf("%s: %s\n", f.Path, f.Reason)
+		}
+	default:
+		return fmt.Errorf("unknown --format %q, must be one of text, json, github", opts.format)
+	}
+
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
@@ -18,34 +18,13 @@ package main
 
 import (
 	"fmt"
-	"io/fs"
 	"os"
-	"path/filepath"
-	"strconv"
-	"strings"
-	"time"
+	"runtime"
 
 	"github.com/spf13/cobra"
-)
 
-var excludeDirsLocations = []string{
-	"external/bazel_tools",
-	".git",
-	"node_modules",
-	"_output",
-	"third_party",
-	"vendor",
-	"verify/boilerplate/test",
-}
-var GENERATED_GO_MARKERS = [7]string{
-	"// Code generated by client-gen. DO NOT EDIT.",
-	"// Code generated by controller-gen. DO NOT EDIT.",
-	"// Code generated by counterfeiter. DO NOT EDIT.",
-	"// Code generated by deepcopy-gen. DO NOT EDIT.",
-	"// Code generated by informer-gen. DO NOT EDIT.",
-	"// Code generated by lister-gen. DO NOT EDIT.",
-	"// Code generated by protoc-gen-go. DO NOT EDIT.",
-}
+	"github.com/mohitsharma-in/k-org/internal/license"
+)
 
 var codeFileExts = map[string]bool{
 	".go":    true,
@@ -63,23 +42,34 @@ var buildFileExts = map[string]bool{
 	"Dockerfile": true,
 }
 
+// defaultHolder is the copyright holder used when rendering headers, matching
+// the tool's historical, Kubernetes-specific boilerplate. It intentionally
+// has no trailing period: templates that want one (apache-2.0, bsd-3-clause)
+// already render "Copyright YEAR HOLDER." themselves.
+const defaultHolder = "The Kubernetes Authors"
+
+// Options is shared by the add and check subcommands: both scan the same
+// tree looking for the same license headers, they just differ in what they
+// do with what they find.
 type Options struct {
-	templatesDir string
-	excludeDirs  []string
-	path         string
-	confirm      bool
-}
-type templateFileType struct {
-	fileExtension    string // store file extension strings like ".sh", "Makefile", etc.
-	templateFileName string // store template file names like "boilerplate.sh.txt", etc
-}
+	excludePatterns  []string
+	excludeFrom      string
+	path             string
+	licenseID        string
+	holder           string
+	jobs             int
+	includeGenerated bool
 
-var templateFileTypes = []templateFileType{
-	{".sh", "boilerplate.sh.txt"},
-	{"Makefile", "boilerplate.Makefile.txt"},
-	{"Dockerfile", "boilerplate.Dockerfile.txt"},
-	{".py", "boilerplate.py.txt"},
-	{".go", "boilerplate.go.txt"},
+	// add-only
+	confirm   bool
+	spdx      bool
+	update    bool
+	yearRange bool
+
+	// check-only
+	format string
+
+	registry *license.Registry
 }
 
 var opts = &Options{}
@@ -89,6 +79,14 @@ func main() {
 		Use:   "k-license",
 		Short: "Tool for Adding license Headers",
 	}
+	rootCmd.PersistentFlags().StringSliceVarP(&opts.excludePatterns, "exclude", "e", nil, "comma-separated list of doublestar glob patterns to exclude, in addition to any .gitignore found while walking and exclude.DefaultPatterns")
+	rootCmd.PersistentFlags().StringVar(&opts.excludeFrom, "exclude-from", "", "file containing additional exclude patterns, one per line")
+	rootCmd.PersistentFlags().StringVar(&opts.path, "path", ".", "Defaults to Current directory")
+	rootCmd.PersistentFlags().StringVar(&opts.licenseID, "license", string(license.Default), "license to apply: apache-2.0, mit, bsd-3-clause, mpl-2.0, gpl-3.0")
+	rootCmd.PersistentFlags().IntVar(&opts.jobs, "jobs", runtime.NumCPU(), "number of workers to scan files with")
+	rootCmd.PersistentFlags().BoolVar(&opts.includeGenerated, "include-generated", false, "don't skip files that look auto-generated")
+	rootCmd.PersistentFlags().StringVar(&opts.holder, "holder", defaultHolder, "copyright holder name to render into headers")
+
 	addCmd := &cobra.Command{
 		Use:   "add",
 		Short: "Add Headers to files",
@@ -96,159 +94,50 @@ func main() {
 			return opts.Run()
 		},
 	}
-
-	addCmd.Flags().StringVar(&opts.templatesDir, "templates", "../../hack/boilerplate", "directory containing license templates")
-	addCmd.Flags().StringSliceVarP(&opts.excludeDirs, "exclude", "e", excludeDirsLocations, "comma-separated list of directories to exclude")
-	addCmd.Flags().StringVar(&opts.path, "path", ".", "Defaults to Current directory")
 	addCmd.Flags().BoolVar(&opts.confirm, "confirm", false, "confirm actually adding license boilerplate to files")
+	addCmd.Flags().BoolVar(&opts.spdx, "spdx", false, "emit a one-line SPDX-License-Identifier header instead of the full license boilerplate")
+	addCmd.Flags().BoolVar(&opts.update, "update", false, "rewrite any existing header (even for a different license) that doesn't match what would be rendered today")
+	addCmd.Flags().BoolVar(&opts.yearRange, "year-range", false, "render a first-seen-current year span (e.g. 2019-2024) using the file's first commit in git, instead of just the current year")
 	rootCmd.AddCommand(addCmd)
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
-	}
-
-}
-func (opts *Options) Run() error {
-	files := 0
-	fileList := make([]string, 0)
-	err := filepath.WalkDir(opts.path, func(path string, info fs.DirEntry, err error) error {
-		if info.IsDir() && containsExcluded(opts.excludeDirs, info.Name()) {
-			return filepath.SkipDir
-		}
-		if !info.IsDir() && (isCodeFile(path) || isBuildFile(path)) && !isGenerateFile(path) {
-			hasLic, err := hasLicense(path)
-			if !hasLic {
-				currentYear := strconv.Itoa(time.Now().Year())
-				if opts.confirm {
-					err := addLicense(path, opts.templatesDir, currentYear)
-					if err != nil {
-						return err
-					}
-					fmt.Printf("Modified %s file\n", path)
-				}
-				fileList = append(fileList, path)
-				files++
-			}
-			return err
-
-		}
-		if err != nil {
-			return err
-		}
-		return nil
-	})
-	if opts.confirm {
-		fmt.Printf("Modified %v files\n", files)
-	} else {
-		fmt.Printf("DRY RUN: No file changes will be made! To make file modifications, rerun the command with  \"--confirm\" flag\n")
-		if files == 0 {
-			fmt.Printf("All files have appropriate License Headers. No changes required.\n")
-		}
-		if files > 0 {
-			fmt.Printf("%v files will be modified to add License Headers\n", files)
-			fmt.Printf("Listing files to be modified:\n")
-			for _, file := range fileList {
-				fmt.Printf("%s\n", file)
-			}
-		}
 
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check that files have a license header, without modifying anything",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.Check()
+		},
 	}
-	return err
-}
+	checkCmd.Flags().StringVar(&opts.format, "format", "text", "output format: text, json, github")
+	rootCmd.AddCommand(checkCmd)
 
-// Looks for the Excluded files/directroy
-func containsExcluded(list []string, str string) bool {
-	for _, item := range list {
-		if item == str {
-			fmt.Printf("Skipping %s as this is Part of exclude list\n", str)
-			return true
-		}
-	}
-	return false
-}
+	addBOMCommand(rootCmd)
 
-// Check if the file is code File
-func isCodeFile(path string) bool {
-	return codeFileExts[strings.ToLower(filepath.Ext(path))]
-}
-
-// Check if the file is build File
-func isBuildFile(path string) bool {
-	return buildFileExts[filepath.Base(path)]
-}
-
-// Checks if the file is auto generated
-func isGenerateFile(path string) bool {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return false
-	}
-	for _, ft := range GENERATED_GO_MARKERS {
-		if strings.Contains(string(data), ft) {
-			fmt.Printf("Skipping File: %s since this is autogenerated file \n", path)
-			return true
-		}
-	}
-	return false
-}
-
-// Checks for license in the file
-func hasLicense(path string) (bool, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return false, err
-	}
-	if strings.Contains(string(data), "Copyright") && strings.Contains(string(data), "Licensed under the Apache License") {
-		fmt.Printf("Skipping File: %s Already have templates added\n", path)
-		return true, nil
-	} else {
-		return false, nil
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
 	}
-}
 
-// Reads templates from directory
-func getTemplateFile(path string) string {
-	for _, file := range templateFileTypes {
-		if strings.HasSuffix(path, file.fileExtension) || filepath.Base(path) == file.fileExtension {
-			return file.templateFileName
-		}
-	}
-	return "boilerplate.tf.txt"
 }
 
-// Adds License Headers
-func addLicense(path, templatesDir, year string) error {
-	tmplData, err := os.ReadFile(filepath.Join(templatesDir, getTemplateFile(path)))
+func (opts *Options) Run() error {
+	findings, err := opts.Scan(opts.confirm)
 	if err != nil {
 		return err
 	}
-	// Replace placeholders with actual values
-	tmpl := strings.ReplaceAll(string(tmplData), "YEAR", year)
-
-	if fileSize(path) {
-		codeData, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
-		newData := append([]byte(tmpl), []byte("\n")...)
-		newData = append(newData, codeData...)
-		return os.WriteFile(path, newData, 0644)
-	}
-	return nil
-}
 
-// Check for empty file
-func fileSize(path string) bool {
-	fileInfo, err := os.Stat(path)
-	if err != nil {
-		fmt.Println(err)
-		return false
+	if opts.confirm {
+		fmt.Printf("Modified %v files\n", len(findings))
+		return nil
 	}
 
-	if fileInfo.Size() == 0 {
-		fmt.Println("The file is empty No Modification Required")
-		return false
-	} else {
-		fmt.Println("The file is not empty")
-		return true
+	fmt.Printf("DRY RUN: No file changes will be made! To make file modifications, rerun the command with  \"--confirm\" flag\n")
+	if len(findings) == 0 {
+		fmt.Printf("All files have appropriate License Headers. No changes required.\n")
+		return nil
+	}
+	fmt.Printf("%v files will be modified to add License Headers\n", len(findings))
+	fmt.Printf("Listing files to be modified:\n")
+	for _, f := range findings {
+		fmt.Printf("%s\n", f.Path)
 	}
+	return nil
 }
@@ -0,0 +1,387 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mohitsharma-in/k-org/internal/exclude"
+	"github.com/mohitsharma-in/k-org/internal/generated"
+	"github.com/mohitsharma-in/k-org/internal/license"
+)
+
+// Finding describes one file that is missing, or doesn't match, its
+// expected license header.
+type Finding struct {
+	Path            string `json:"path"`
+	Reason          string `json:"reason"`
+	ExpectedLicense string `json:"expected_license"`
+}
+
+// Scan walks opts.path and returns a Finding for every code or build file
+// that doesn't already carry a registered license header, reading each file
+// exactly once across a pool of opts.jobs workers. When apply is true, a
+// missing header is rewritten into the file as it's found; Check always
+// passes false since it must never modify files.
+//
+// It is shared by the add command (apply=opts.confirm) and the check
+// command (apply=false).
+func (opts *Options) Scan(apply bool) ([]Finding, error) {
+	registry, err := license.NewRegistry()
+	if err != nil {
+		return nil, err
+	}
+	opts.registry = registry
+
+	id := license.ID(opts.licenseID)
+	if !registry.Has(id) {
+		return nil, fmt.Errorf("unknown --license %q, must be one of %v", opts.licenseID, registry.IDs())
+	}
+
+	patterns := append([]string{}, exclude.DefaultPatterns...)
+	patterns = append(patterns, opts.excludePatterns...)
+	if opts.excludeFrom != "" {
+		extra, err := exclude.PatternsFromFile(opts.excludeFrom)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, extra...)
+	}
+	matcher, err := exclude.New(opts.path, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []string
+	err = filepath.WalkDir(opts.path, func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if matcher.Match(path, info.IsDir()) {
+			if info.IsDir() {
+				fmt.Printf("Skipping %s as this is Part of exclude list\n", path)
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() || !(isCodeFile(path) || isBuildFile(path)) {
+			return nil
+		}
+		candidates = append(candidates, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := opts.jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	paths := make(chan string)
+	go func() {
+		defer close(paths)
+		for _, path := range candidates {
+			paths <- path
+		}
+	}()
+
+	type outcome struct {
+		finding *Finding
+		err     error
+	}
+	outcomes := make(chan outcome)
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				f, err := opts.processFile(path, registry, id, apply)
+				outcomes <- outcome{finding: f, err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	var findings []Finding
+	var firstErr error
+	for o := range outcomes {
+		if o.err != nil && firstErr == nil {
+			firstErr = o.err
+		}
+		if o.finding != nil {
+			findings = append(findings, *o.finding)
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Path < findings[j].Path })
+	return findings, nil
+}
+
+// processFile reads path once and decides whether it needs a license
+// header, optionally rewriting it in place when apply is true. In --update
+// mode it also catches headers that exist but are stale: the wrong
+// license, or the right one with an outdated year or holder.
+func (opts *Options) processFile(path string, registry *license.Registry, id license.ID, apply bool) (*Finding, error) {
+	// A nil head checks only the filename conventions, before any read.
+	if !opts.includeGenerated && generated.IsGenerated(path, nil) {
+		fmt.Printf("Skipping File: %s since this is autogenerated file \n", path)
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.includeGenerated && generated.IsGenerated(path, data) {
+		fmt.Printf("Skipping File: %s since this is autogenerated file \n", path)
+		return nil, nil
+	}
+
+	ext := fileExtension(path)
+
+	if opts.update {
+		return opts.processUpdate(path, data, ext, registry, id, apply)
+	}
+
+	if _, ok := registry.Detect(data); ok {
+		fmt.Printf("Skipping File: %s Already have templates added\n", path)
+		return nil, nil
+	}
+
+	year, err := opts.yearFor(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := renderHeader(registry, id, ext, year, opts.holder, opts.spdx); !ok {
+		fmt.Printf("No %s template registered for %s, skipping\n", id, path)
+		return nil, nil
+	}
+
+	finding := &Finding{
+		Path:            path,
+		Reason:          "missing license header",
+		ExpectedLicense: id.SPDX(),
+	}
+
+	if apply {
+		if err := opts.writeHeader(path, data, 0, 0, registry, id, ext); err != nil {
+			return nil, err
+		}
+		fmt.Printf("Modified %s file\n", path)
+	}
+	return finding, nil
+}
+
+// processUpdate implements --update: it renders the header path should have
+// and, if the file's existing header (of any registered license) doesn't
+// match byte-for-byte, replaces it; a file with no header at all is treated
+// the same as the non-update missing-header case.
+func (opts *Options) processUpdate(path string, data []byte, ext string, registry *license.Registry, id license.ID, apply bool) (*Finding, error) {
+	year, err := opts.yearFor(path)
+	if err != nil {
+		return nil, err
+	}
+	header, ok := renderHeader(registry, id, ext, year, opts.holder, opts.spdx)
+	if !ok {
+		fmt.Printf("No %s template registered for %s, skipping\n", id, path)
+		return nil, nil
+	}
+
+	existingID, start, end, found := registry.DetectAnyHeader(ext, data)
+	if found && existingID == id && string(data[start:end]) == header {
+		return nil, nil
+	}
+
+	reason := "missing license header"
+	if found {
+		reason = fmt.Sprintf("stale %s header, expected %s", existingID.SPDX(), id.SPDX())
+	}
+	finding := &Finding{Path: path, Reason: reason, ExpectedLicense: id.SPDX()}
+
+	if apply {
+		if len(data) == 0 {
+			fmt.Printf("The file %s is empty, no modification required\n", path)
+			return finding, nil
+		}
+		if err := atomicWrite(path, spliceHeader(data, start, end, header)); err != nil {
+			return nil, err
+		}
+		fmt.Printf("Updated %s file\n", path)
+	}
+	return finding, nil
+}
+
+// Check if the file is code File
+func isCodeFile(path string) bool {
+	return codeFileExts[strings.ToLower(filepath.Ext(path))]
+}
+
+// Check if the file is build File
+func isBuildFile(path string) bool {
+	return buildFileExts[filepath.Base(path)]
+}
+
+// fileExtension returns the key a file uses to look up its license template:
+// its extension, or its base name for extensionless conventions like
+// Makefile and Dockerfile.
+func fileExtension(path string) string {
+	if buildFileExts[filepath.Base(path)] {
+		return filepath.Base(path)
+	}
+	return filepath.Ext(path)
+}
+
+// writeHeader renders the license header for path and inserts it atomically
+// at data[start:end] (0,0 for a fresh file with no existing header), so an
+// interrupted run never leaves a half-written file.
+func (opts *Options) writeHeader(path string, data []byte, start, end int, registry *license.Registry, id license.ID, ext string) error {
+	if len(data) == 0 {
+		fmt.Printf("The file %s is empty, no modification required\n", path)
+		return nil
+	}
+
+	year, err := opts.yearFor(path)
+	if err != nil {
+		return err
+	}
+	header, ok := renderHeader(registry, id, ext, year, opts.holder, opts.spdx)
+	if !ok {
+		fmt.Printf("No %s template registered for %s, skipping\n", id, path)
+		return nil
+	}
+
+	return atomicWrite(path, spliceHeader(data, start, end, header))
+}
+
+// renderHeader renders the full boilerplate or, in --spdx mode, the one-line
+// SPDX header for (id, fileExt).
+func renderHeader(registry *license.Registry, id license.ID, fileExt, year, holder string, spdx bool) (string, bool) {
+	if spdx {
+		return registry.SPDXHeader(id, fileExt, year, holder)
+	}
+	return registry.Render(id, fileExt, year, holder)
+}
+
+// spliceHeader returns data with the byte range [start:end) replaced by
+// header. start==end==0 means there's no existing header to replace, in
+// which case a blank line is inserted to separate header from code, matching
+// how a freshly added header has always looked.
+func spliceHeader(data []byte, start, end int, header string) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(header)+1)
+	out = append(out, data[:start]...)
+	out = append(out, []byte(header)...)
+	if start == end {
+		out = append(out, '\n')
+	}
+	out = append(out, data[end:]...)
+	return out
+}
+
+// yearFor returns the YEAR value to render into path's header: the current
+// year, or, with --year-range, a "first-seen-current" span derived from the
+// file's first commit in git.
+func (opts *Options) yearFor(path string) (string, error) {
+	if !opts.yearRange {
+		return currentYear(), nil
+	}
+	firstYear, err := gitFirstCommitYear(path)
+	if err != nil || firstYear == "" {
+		// No git history (new file, not a repo, etc): fall back to the plain
+		// current year rather than failing the whole run.
+		return currentYear(), nil
+	}
+	current := currentYear()
+	if firstYear == current {
+		return current, nil
+	}
+	return firstYear + "-" + current, nil
+}
+
+// gitFirstCommitYear returns the year path was first added to git, following
+// renames, or "" if that can't be determined (not a repo, untracked file).
+func gitFirstCommitYear(path string) (string, error) {
+	cmd := exec.Command("git", "log", "--diff-filter=A", "--follow", "--format=%aI", "--", path)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", nil
+	}
+	lines := strings.Fields(strings.TrimSpace(string(out)))
+	if len(lines) == 0 {
+		return "", nil
+	}
+	// git log lists newest first, so the addition commit is the last line.
+	firstCommit := lines[len(lines)-1]
+	if len(firstCommit) < 4 {
+		return "", nil
+	}
+	return firstCommit[:4], nil
+}
+
+// atomicWrite writes data to path via a temp file in the same directory
+// followed by a rename, so a crash or interrupt never leaves path holding a
+// partial write.
+func atomicWrite(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".k-license-tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// currentYear returns the current year as used to render YEAR placeholders.
+func currentYear() string {
+	return strconv.Itoa(time.Now().Year())
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSpliceHeaderInsertsBlankLineWhenNoExistingHeader(t *testing.T) {
+	data := []byte("package foo\n")
+	got := spliceHeader(data, 0, 0, "// header\n")
+	want := "// header\n\npackage foo\n"
+	if string(got) != want {
+		t.Errorf("spliceHeader() = %q, want %q", got, want)
+	}
+}
+
+func TestSpliceHeaderReplacesExistingRange(t *testing.T) {
+	data := []byte("// old header\npackage foo\n")
+	start, end := 0, len("// old header\n")
+	got := spliceHeader(data, start, end, "// new header\n")
+	want := "// new header\npackage foo\n"
+	if string(got) != want {
+		t.Errorf("spliceHeader() = %q, want %q", got, want)
+	}
+}
+
+func TestAtomicWritePreservesMode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "foo.go")
+	if err := os.WriteFile(path, []byte("package foo\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := atomicWrite(path, []byte("// header\npackage foo\n")); err != nil {
+		t.Fatalf("atomicWrite: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "// header\npackage foo\n" {
+		t.Errorf("file contents = %q, want header prepended", got)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("file mode = %v, want 0755", info.Mode().Perm())
+	}
+}
+
+func TestFileExtension(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"foo.go", ".go"},
+		{"dir/Makefile", "Makefile"},
+		{"dir/Dockerfile", "Dockerfile"},
+		{"script.sh", ".sh"},
+	}
+	for _, tt := range tests {
+		if got := fileExtension(tt.path); got != tt.want {
+			t.Errorf("fileExtension(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
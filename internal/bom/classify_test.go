@@ -0,0 +1,42 @@
+package bom
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	apache, ok := mustLoadCanonicalText(t, "texts/apache-2.0.txt")
+	if !ok {
+		t.Fatal("loading apache-2.0.txt")
+	}
+	mit, ok := mustLoadCanonicalText(t, "texts/mit.txt")
+	if !ok {
+		t.Fatal("loading mit.txt")
+	}
+
+	tests := []struct {
+		name      string
+		text      string
+		threshold float64
+		wantType  LicenseType
+	}{
+		{"exact apache-2.0 text", apache, 0.9, Apache20},
+		{"exact mit text", mit, 0.9, MIT},
+		{"unrelated text", "this is just some readme content, not a license at all", 0.9, Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, score := Classify(tt.text, tt.threshold)
+			if got != tt.wantType {
+				t.Errorf("Classify() = (%q, %.3f), want type %q", got, score, tt.wantType)
+			}
+		})
+	}
+}
+
+func mustLoadCanonicalText(t *testing.T, path string) (string, bool) {
+	t.Helper()
+	data, err := textFS.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data), true
+}
@@ -0,0 +1,135 @@
+package bom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ModuleInfo identifies one dependency and where its source can be found on
+// disk, either in the module cache or a vendor/ directory.
+type ModuleInfo struct {
+	Path    string
+	Version string
+	Dir     string
+}
+
+// licenseFileNames are the conventional names for a module's license file,
+// checked in order directly inside a module's directory.
+var licenseFileNames = []string{
+	"LICENSE",
+	"LICENSE.txt",
+	"LICENSE.md",
+	"LICENSE-APACHE",
+	"LICENSE-MIT",
+	"COPYING",
+	"COPYING.txt",
+}
+
+// Dependencies discovers the dependencies of the Go module rooted at dir,
+// preferring a vendor/ directory when present (no network access required)
+// and falling back to the module cache via "go mod download -json".
+func Dependencies(dir string) ([]ModuleInfo, error) {
+	vendorDir := filepath.Join(dir, "vendor")
+	if info, err := os.Stat(vendorDir); err == nil && info.IsDir() {
+		return VendorDependencies(vendorDir)
+	}
+	return ModuleDependencies(dir)
+}
+
+// ModuleDependencies runs "go mod download -json" in dir and returns the
+// path, version, and module-cache directory of each dependency.
+func ModuleDependencies(dir string) ([]ModuleInfo, error) {
+	cmd := exec.Command("go", "mod", "download", "-json")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("bom: go mod download: %w", err)
+	}
+
+	var modules []ModuleInfo
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var m struct {
+			Path    string
+			Version string
+			Dir     string
+		}
+		if err := dec.Decode(&m); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("bom: parsing go mod download output: %w", err)
+		}
+		modules = append(modules, ModuleInfo{Path: m.Path, Version: m.Version, Dir: m.Dir})
+	}
+	return modules, nil
+}
+
+// VendorDependencies lists the modules recorded in vendorDir/modules.txt,
+// with the version of each, sorted by path. It does not walk vendorDir
+// itself and returns an error if modules.txt is missing.
+func VendorDependencies(vendorDir string) ([]ModuleInfo, error) {
+	versions, err := parseVendorModulesTxt(filepath.Join(vendorDir, "modules.txt"))
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []ModuleInfo
+	for path, version := range versions {
+		modules = append(modules, ModuleInfo{
+			Path:    path,
+			Version: version,
+			Dir:     filepath.Join(vendorDir, filepath.FromSlash(path)),
+		})
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Path < modules[j].Path })
+	return modules, nil
+}
+
+// parseVendorModulesTxt reads the "# module/path version" lines emitted by
+// "go mod vendor" into modules.txt.
+func parseVendorModulesTxt(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("bom: reading %s: %w", path, err)
+	}
+	defer f.Close()
+
+	versions := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "# "))
+		if len(fields) >= 2 && strings.HasPrefix(fields[1], "v") {
+			versions[fields[0]] = fields[1]
+		}
+	}
+	return versions, scanner.Err()
+}
+
+// FindLicenseFile returns the contents of the first conventionally named
+// license file found directly inside dir, or "" if none exists.
+func FindLicenseFile(dir string) (string, error) {
+	for _, name := range licenseFileNames {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return string(data), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	return "", nil
+}
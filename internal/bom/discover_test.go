@@ -0,0 +1,34 @@
+package bom
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVendorDependenciesSortedByPath(t *testing.T) {
+	vendorDir := t.TempDir()
+	modulesTxt := "# github.com/zzz/last v1.0.0\n" +
+		"## explicit\n" +
+		"# github.com/aaa/first v2.0.0\n" +
+		"## explicit\n" +
+		"# github.com/mmm/middle v3.0.0\n" +
+		"## explicit\n"
+	if err := os.WriteFile(filepath.Join(vendorDir, "modules.txt"), []byte(modulesTxt), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modules, err := VendorDependencies(vendorDir)
+	if err != nil {
+		t.Fatalf("VendorDependencies: %v", err)
+	}
+	if len(modules) != 3 {
+		t.Fatalf("got %d modules, want 3", len(modules))
+	}
+	want := []string{"github.com/aaa/first", "github.com/mmm/middle", "github.com/zzz/last"}
+	for i, m := range modules {
+		if m.Path != want[i] {
+			t.Errorf("modules[%d].Path = %q, want %q", i, m.Path, want[i])
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Package exclude decides whether a path encountered while walking a repo
+// should be skipped: either because it matches a doublestar glob pattern or
+// because a .gitignore file found along the way says so.
+package exclude
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	ignore "github.com/sabhiram/go-gitignore"
+)
+
+// DefaultPatterns covers directories and generated files commonly found in
+// Kubernetes-style repos that a plain directory-basename match can't catch,
+// such as generated files living outside a dedicated directory.
+var DefaultPatterns = []string{
+	".git/**",
+	"**/node_modules/**",
+	"**/_output/**",
+	"**/third_party/**",
+	"**/vendor/**",
+	"external/bazel_tools/**",
+	"verify/boilerplate/test/**",
+	"**/zz_generated.*.go",
+	"**/*.pb.go",
+	"**/*_generated.go",
+	"**/*.gen.go",
+}
+
+// gitignoreMatcher pairs a compiled .gitignore with the directory it came
+// from, since the patterns inside it are relative to that directory.
+type gitignoreMatcher struct {
+	dir     string
+	ignorer *ignore.GitIgnore
+}
+
+// Matcher decides whether a path found under root should be excluded from a
+// k-license walk.
+type Matcher struct {
+	root       string
+	patterns   []string
+	gitignores []gitignoreMatcher
+}
+
+// New builds a Matcher for root out of patterns (doublestar globs, relative
+// to root) plus every .gitignore file found under root.
+func New(root string, patterns []string) (*Matcher, error) {
+	m := &Matcher{root: root, patterns: patterns}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != ".gitignore" {
+			return nil
+		}
+		ignorer, err := ignore.CompileIgnoreFile(path)
+		if err != nil {
+			return err
+		}
+		m.gitignores = append(m.gitignores, gitignoreMatcher{dir: filepath.Dir(path), ignorer: ignorer})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PatternsFromFile reads additional doublestar patterns from an
+// --exclude-from file, one pattern per line, ignoring blank lines and "#"
+// comments.
+func PatternsFromFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+// Match reports whether path, found while walking m.root, should be
+// excluded. isDir lets directory patterns like "vendor/**" match the
+// directory itself, not just its contents.
+func (m *Matcher) Match(path string, isDir bool) bool {
+	rel, err := filepath.Rel(m.root, path)
+	if err != nil {
+		rel = path
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, pattern := range m.patterns {
+		if ok, _ := doublestar.Match(pattern, rel); ok {
+			return true
+		}
+		if isDir {
+			if ok, _ := doublestar.Match(pattern, rel+"/"); ok {
+				return true
+			}
+		}
+	}
+
+	for _, gi := range m.gitignores {
+		giRel, err := filepath.Rel(gi.dir, path)
+		if err != nil || strings.HasPrefix(giRel, "..") {
+			continue
+		}
+		giRel = filepath.ToSlash(giRel)
+		if isDir {
+			giRel += "/"
+		}
+		if gi.ignorer.MatchesPath(giRel) {
+			return true
+		}
+	}
+
+	return false
+}
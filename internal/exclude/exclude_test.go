@@ -0,0 +1,68 @@
+package exclude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchDoublestarPatterns(t *testing.T) {
+	root := t.TempDir()
+
+	m, err := New(root, []string{"**/vendor/**"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"vendor directory itself", filepath.Join(root, "vendor"), true, true},
+		{"file under vendor", filepath.Join(root, "vendor", "foo", "bar.go"), false, true},
+		{"unrelated file", filepath.Join(root, "pkg", "foo.go"), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Match(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchGitignore(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "build"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("build/\n*.log\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := New(root, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"ignored directory", filepath.Join(root, "build"), true, true},
+		{"file under ignored directory", filepath.Join(root, "build", "out.bin"), false, true},
+		{"ignored file pattern", filepath.Join(root, "debug.log"), false, true},
+		{"unrelated file", filepath.Join(root, "main.go"), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Match(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Package generated detects files that were produced by a code generator
+// and so shouldn't have a license header added to them.
+package generated
+
+import (
+	"path/filepath"
+	"regexp"
+)
+
+// headSize is how much of a file's contents IsGenerated inspects for a
+// marker comment. Generator markers always appear near the top of a file,
+// so there's no need to read more than this.
+const headSize = 4096
+
+// markersByExt matches generator marker comments against the file types
+// they're documented for, keyed the same way fileExtension in cmd/k-license
+// looks up a file's template: its extension, or its base name for
+// extensionless conventions like Makefile. Scoping by file type keeps, e.g.,
+// the shell "DO NOT EDIT" marker from matching a shell script embedded as a
+// string literal inside an ordinary .go file. The Go pattern follows the
+// convention documented at
+// https://pkg.go.dev/cmd/go#hdr-Generate_Go_files_by_processing_source.
+var markersByExt = map[string][]*regexp.Regexp{
+	".go":      {regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)},
+	".py":      {regexp.MustCompile(`(?m)^# Generated by .*$`)},
+	".sh":      {regexp.MustCompile(`(?m)^#.*DO NOT EDIT\.?\s*$`)},
+	"Makefile": {regexp.MustCompile(`(?m)^#.*DO NOT EDIT\.?\s*$`)},
+	".java":    {regexp.MustCompile(`(?m)^\s*@Generated\b`)},
+	".c":       {regexp.MustCompile(`/\*\s*Automatically generated\s*\*/`)},
+	".h":       {regexp.MustCompile(`/\*\s*Automatically generated\s*\*/`)},
+	".cpp":     {regexp.MustCompile(`/\*\s*Automatically generated\s*\*/`)},
+}
+
+// markerKey returns the key path uses to look up its generator markers in
+// markersByExt: its extension, or its base name for extensionless
+// conventions like Makefile.
+func markerKey(path string) string {
+	base := filepath.Base(path)
+	if base == "Makefile" {
+		return base
+	}
+	return filepath.Ext(path)
+}
+
+// filenamePatterns match generated-file naming conventions that k-license
+// skips without reading the file at all.
+var filenamePatterns = []string{
+	"zz_generated_*.go",
+	"zz_generated.*.go",
+	"*.pb.go",
+	"*_generated.go",
+	"*.gen.go",
+}
+
+// IsGenerated reports whether path is a generated file: either its name
+// follows a known generated-file convention, or head (the first ~4KB of its
+// contents) contains a generator marker comment.
+func IsGenerated(path string, head []byte) bool {
+	base := filepath.Base(path)
+	for _, pattern := range filenamePatterns {
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+	}
+
+	if len(head) > headSize {
+		head = head[:headSize]
+	}
+	for _, marker := range markersByExt[markerKey(path)] {
+		if marker.Match(head) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package generated
+
+import "testing"
+
+func TestIsGenerated(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		head []byte
+		want bool
+	}{
+		{
+			name: "go generated marker",
+			path: "zz.go",
+			head: []byte("// Code generated by foo. DO NOT EDIT.\npackage foo\n"),
+			want: true,
+		},
+		{
+			name: "go file with an embedded shell script is not generated",
+			path: "bootstrap.go",
+			head: []byte("package foo\n\nconst script = `#!/bin/sh\n# DO NOT EDIT.\necho hi\n`\n"),
+			want: false,
+		},
+		{
+			name: "shell DO NOT EDIT marker",
+			path: "bootstrap.sh",
+			head: []byte("#!/bin/sh\n# DO NOT EDIT.\necho hi\n"),
+			want: true,
+		},
+		{
+			name: "python generated marker",
+			path: "models.py",
+			head: []byte("# Generated by protoc-gen-python\nimport foo\n"),
+			want: true,
+		},
+		{
+			name: "filename convention",
+			path: "zz_generated.deepcopy.go",
+			head: []byte("package foo\n"),
+			want: true,
+		},
+		{
+			name: "plain file",
+			path: "main.go",
+			head: []byte("package main\n"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsGenerated(tt.path, tt.head); got != tt.want {
+				t.Errorf("IsGenerated(%q, ...) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+// Package license provides the set of license templates k-license knows how
+// to render and detect. Templates are embedded into the binary so the tool
+// has no runtime dependency on an external boilerplate directory.
+package license
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+const templatesRoot = "templates"
+
+// ID identifies a supported license by the directory name its templates
+// live under. It intentionally matches the lowercase form of the license's
+// SPDX identifier so it can double as a --license flag value.
+type ID string
+
+// Supported licenses.
+const (
+	Apache20   ID = "apache-2.0"
+	MIT        ID = "mit"
+	BSD3Clause ID = "bsd-3-clause"
+	MPL20      ID = "mpl-2.0"
+	GPL30      ID = "gpl-3.0"
+)
+
+// Default is the license used when --license is not given, preserving the
+// tool's historical behavior.
+const Default = Apache20
+
+var spdxIdentifiers = map[ID]string{
+	Apache20:   "Apache-2.0",
+	MIT:        "MIT",
+	BSD3Clause: "BSD-3-Clause",
+	MPL20:      "MPL-2.0",
+	GPL30:      "GPL-3.0",
+}
+
+// fingerprints holds a substring unique to each license's rendered body,
+// used to recognize an existing header without relying on the exact
+// copyright year or holder that was filled in when it was added.
+var fingerprints = map[ID]string{
+	Apache20:   "Licensed under the Apache License, Version 2.0",
+	MIT:        "Permission is hereby granted, free of charge, to any person obtaining a copy",
+	BSD3Clause: "Redistribution and use in source and binary forms, with or without",
+	MPL20:      "This Source Code Form is subject to the terms of the Mozilla Public",
+	GPL30:      "This program is free software: you can redistribute it and/or modify",
+}
+
+// SPDX returns the canonical SPDX identifier for id, e.g. "Apache-2.0".
+func (id ID) SPDX() string {
+	return spdxIdentifiers[id]
+}
+
+// String implements fmt.Stringer so ID prints as its flag value.
+func (id ID) String() string {
+	return string(id)
+}
+
+// entryKey indexes a single template by license and file extension (or, for
+// extensionless conventions like Makefile/Dockerfile, the file's base name).
+type entryKey struct {
+	id  ID
+	ext string
+}
+
+// spdxKey indexes a compiled SPDX-header-matching pattern by license and
+// comment style. The one-line SPDX header's shape only depends on which
+// comment syntax the file extension uses, not the extension itself, so
+// there's no need to key this per-extension the way headerRe is.
+type spdxKey struct {
+	id     ID
+	prefix string
+}
+
+// Registry maps (license, file extension) pairs to the header template that
+// should be used for them, and knows how to detect any registered license
+// in a file's existing contents.
+type Registry struct {
+	templates    map[entryKey]string
+	headerRe     map[entryKey]*regexp.Regexp
+	spdxHeaderRe map[spdxKey]*regexp.Regexp
+}
+
+// NewRegistry loads the embedded templates into a Registry.
+func NewRegistry() (*Registry, error) {
+	r := &Registry{
+		templates:    make(map[entryKey]string),
+		headerRe:     make(map[entryKey]*regexp.Regexp),
+		spdxHeaderRe: make(map[spdxKey]*regexp.Regexp),
+	}
+	err := fs.WalkDir(templateFS, templatesRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".txt") {
+			return nil
+		}
+		rel, err := filepath.Rel(templatesRoot, path)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(rel, string(filepath.Separator))
+		if len(parts) != 2 {
+			return fmt.Errorf("license: unexpected template path %q", path)
+		}
+		id := ID(parts[0])
+		ext := strings.TrimSuffix(strings.TrimPrefix(parts[1], "boilerplate"), ".txt")
+		if ext == ".Makefile" || ext == ".Dockerfile" {
+			ext = strings.TrimPrefix(ext, ".")
+		}
+		data, err := templateFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		key := entryKey{id: id, ext: ext}
+		r.templates[key] = string(data)
+		r.headerRe[key] = compileHeaderPattern(string(data))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for id := range spdxIdentifiers {
+		for _, prefix := range []string{"#", "//"} {
+			r.spdxHeaderRe[spdxKey{id: id, prefix: prefix}] = compileHeaderPattern(spdxBody(id.SPDX(), prefix, "YEAR", "HOLDER"))
+		}
+	}
+	return r, nil
+}
+
+// compileHeaderPattern turns a rendered template into a regexp that matches
+// it with YEAR and HOLDER treated as wildcards, so a header with a stale
+// year or holder name is still recognized as that license's header.
+func compileHeaderPattern(tmpl string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(tmpl)
+	pattern = strings.ReplaceAll(pattern, "YEAR", `\d{4}(?:-\d{4})?`)
+	pattern = strings.ReplaceAll(pattern, "HOLDER", `.+?`)
+	return regexp.MustCompile(pattern)
+}
+
+// IDs returns the registered license IDs, sorted so the list printed for an
+// unknown --license value is stable.
+func (r *Registry) IDs() []ID {
+	seen := make(map[ID]bool)
+	for k := range r.templates {
+		seen[k.id] = true
+	}
+	ids := make([]ID, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Has reports whether id is a registered license.
+func (r *Registry) Has(id ID) bool {
+	for k := range r.templates {
+		if k.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Render fills in the YEAR and HOLDER placeholders of the full license
+// header template registered for (id, fileExt) and returns it. The second
+// return value is false if no template is registered for that combination.
+func (r *Registry) Render(id ID, fileExt, year, holder string) (string, bool) {
+	tmpl, ok := r.templates[entryKey{id: id, ext: fileExt}]
+	if !ok {
+		return "", false
+	}
+	tmpl = strings.ReplaceAll(tmpl, "YEAR", year)
+	tmpl = holderPlaceholder.ReplaceAllLiteralString(tmpl, holder)
+	return tmpl, true
+}
+
+// holderPlaceholder matches HOLDER only as a whole word, so license text
+// such as "COPYRIGHT HOLDERS" is left as it is.
+var holderPlaceholder = regexp.MustCompile(`\bHOLDER\b`)
+
+// SPDXHeader renders a one-line SPDX identifier plus a copyright line,
+// commented for fileExt, in place of the full license boilerplate.
+func (r *Registry) SPDXHeader(id ID, fileExt, year, holder string) (string, bool) {
+	spdx := id.SPDX()
+	if spdx == "" {
+		return "", false
+	}
+	return spdxBody(spdx, commentPrefix(fileExt), year, holder), true
+}
+
+// spdxBody renders the one-line SPDX identifier plus copyright line body
+// shared by SPDXHeader and the spdxHeaderRe patterns used to detect it.
+func spdxBody(spdx, prefix, year, holder string) string {
+	return fmt.Sprintf("%s SPDX-License-Identifier: %s\n%s Copyright %s %s\n", prefix, spdx, prefix, year, holder)
+}
+
+// commentPrefix returns the line-comment marker used by fileExt.
+func commentPrefix(fileExt string) string {
+	switch fileExt {
+	case ".py", ".sh", "Makefile", "Dockerfile":
+		return "#"
+	default:
+		return "//"
+	}
+}
+
+// Detect reports whether data already contains a header for any registered
+// license, identified by an SPDX identifier line or a license fingerprint.
+func (r *Registry) Detect(data []byte) (ID, bool) {
+	text := string(data)
+	for id, spdx := range spdxIdentifiers {
+		if strings.Contains(text, "SPDX-License-Identifier: "+spdx) {
+			return id, true
+		}
+	}
+	for id, fingerprint := range fingerprints {
+		if strings.Contains(text, fingerprint) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// DetectAnyHeader looks for a header matching any license registered for
+// fileExt, with YEAR and HOLDER treated as wildcards, and returns its byte
+// range in data. It recognizes both the full boilerplate form and the
+// one-line SPDX form, so --update can find a stale header to replace
+// regardless of which one was originally rendered.
+func (r *Registry) DetectAnyHeader(fileExt string, data []byte) (id ID, start, end int, ok bool) {
+	for key, re := range r.headerRe {
+		if key.ext != fileExt {
+			continue
+		}
+		if loc := re.FindIndex(data); loc != nil {
+			return key.id, loc[0], loc[1], true
+		}
+	}
+	prefix := commentPrefix(fileExt)
+	for key, re := range r.spdxHeaderRe {
+		if key.prefix != prefix {
+			continue
+		}
+		if loc := re.FindIndex(data); loc != nil {
+			return key.id, loc[0], loc[1], true
+		}
+	}
+	return "", 0, 0, false
+}
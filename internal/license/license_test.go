@@ -0,0 +1,124 @@
+package license
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	return r
+}
+
+func TestDetect(t *testing.T) {
+	r := newTestRegistry(t)
+
+	tests := []struct {
+		name    string
+		data    string
+		wantID  ID
+		wantHit bool
+	}{
+		{
+			name:    "SPDX identifier",
+			data:    "// SPDX-License-Identifier: MIT\npackage foo\n",
+			wantID:  MIT,
+			wantHit: true,
+		},
+		{
+			name:    "fingerprint without SPDX line",
+			data:    "/*\nLicensed under the Apache License, Version 2.0\n*/\npackage foo\n",
+			wantID:  Apache20,
+			wantHit: true,
+		},
+		{
+			name:    "no header",
+			data:    "package foo\n",
+			wantHit: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := r.Detect([]byte(tt.data))
+			if ok != tt.wantHit {
+				t.Fatalf("Detect() ok = %v, want %v", ok, tt.wantHit)
+			}
+			if ok && id != tt.wantID {
+				t.Fatalf("Detect() id = %q, want %q", id, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestDetectAnyHeaderBoilerplate(t *testing.T) {
+	r := newTestRegistry(t)
+
+	header, ok := r.Render(MIT, ".go", "2020", "Jane Doe")
+	if !ok {
+		t.Fatal("Render(MIT, .go) returned false")
+	}
+	data := []byte(header + "\npackage foo\n")
+
+	id, start, end, found := r.DetectAnyHeader(".go", data)
+	if !found {
+		t.Fatal("DetectAnyHeader() found = false, want true")
+	}
+	if id != MIT {
+		t.Fatalf("DetectAnyHeader() id = %q, want %q", id, MIT)
+	}
+	if got := string(data[start:end]); got != header {
+		t.Fatalf("DetectAnyHeader() matched %q, want %q", got, header)
+	}
+}
+
+func TestDetectAnyHeaderSPDX(t *testing.T) {
+	r := newTestRegistry(t)
+
+	header, ok := r.SPDXHeader(Apache20, ".go", "2020", "Jane Doe")
+	if !ok {
+		t.Fatal("SPDXHeader(Apache20, .go) returned false")
+	}
+	data := []byte(header + "\npackage foo\n")
+
+	id, start, end, found := r.DetectAnyHeader(".go", data)
+	if !found {
+		t.Fatal("DetectAnyHeader() found = false, want true")
+	}
+	if id != Apache20 {
+		t.Fatalf("DetectAnyHeader() id = %q, want %q", id, Apache20)
+	}
+	if got := string(data[start:end]); got != header {
+		t.Fatalf("DetectAnyHeader() matched %q, want %q", got, header)
+	}
+}
+
+func TestDetectAnyHeaderSPDXShellComment(t *testing.T) {
+	r := newTestRegistry(t)
+
+	header, ok := r.SPDXHeader(BSD3Clause, ".sh", "2020", "Jane Doe")
+	if !ok {
+		t.Fatal("SPDXHeader(BSD3Clause, .sh) returned false")
+	}
+	if !strings.HasPrefix(header, "# SPDX-License-Identifier:") {
+		t.Fatalf("SPDXHeader(.sh) = %q, want a '#'-commented header", header)
+	}
+
+	data := []byte(header + "\necho hi\n")
+	id, _, _, found := r.DetectAnyHeader(".sh", data)
+	if !found || id != BSD3Clause {
+		t.Fatalf("DetectAnyHeader() = (%q, %v), want (%q, true)", id, found, BSD3Clause)
+	}
+}
+
+func TestDetectAnyHeaderNoHeader(t *testing.T) {
+	r := newTestRegistry(t)
+
+	_, _, _, found := r.DetectAnyHeader(".go", []byte("package foo\n"))
+	if found {
+		t.Fatal("DetectAnyHeader() found = true for a file with no header")
+	}
+}